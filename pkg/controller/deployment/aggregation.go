@@ -0,0 +1,65 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AggregateToManagerLabel, when set to "true" on a ClusterRole, marks it as a
+// satellite that should be unioned into the operator's manager ClusterRole
+// wherever that ClusterRole's AggregationRule selects it. Cluster admins use
+// this to grant the operator extra verbs for BYO-issuer CRDs and other
+// third-party plugins without hand-editing config/rbac/role.yaml.
+const AggregateToManagerLabel = "cert-manager-operator.openshift.io/aggregate-to-manager"
+
+// ClusterRoleLister resolves the ClusterRoles matching a label selector, so
+// that aggregation can be computed either against a live cluster or a
+// fixture used by tests.
+type ClusterRoleLister interface {
+	ListClusterRoles(ctx context.Context, selector labels.Selector) ([]rbacv1.ClusterRole, error)
+}
+
+// ResolveAggregatedRules returns the effective PolicyRules for clusterRole:
+// its own Rules plus, if it has a non-nil AggregationRule, the union of Rules
+// from every ClusterRole matched by each of its ClusterRoleSelectors.
+func ResolveAggregatedRules(ctx context.Context, clusterRole *rbacv1.ClusterRole, lister ClusterRoleLister) ([]rbacv1.PolicyRule, error) {
+	rules := append([]rbacv1.PolicyRule{}, clusterRole.Rules...)
+
+	if clusterRole.AggregationRule == nil {
+		return rules, nil
+	}
+
+	for i := range clusterRole.AggregationRule.ClusterRoleSelectors {
+		selector := clusterRole.AggregationRule.ClusterRoleSelectors[i]
+		labelSelector, err := metav1.LabelSelectorAsSelector(&selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clusterRoleSelector on %s: %w", clusterRole.Name, err)
+		}
+
+		matched, err := lister.ListClusterRoles(ctx, labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list aggregated ClusterRoles for %s: %w", clusterRole.Name, err)
+		}
+
+		for _, satellite := range matched {
+			rules = append(rules, satellite.Rules...)
+		}
+	}
+
+	return rules, nil
+}
+
+// NewRBACValidatorWithAggregation builds an RBACValidator whose operator
+// permissions are the aggregated view of clusterRole: its own rules unioned
+// with every satellite ClusterRole its AggregationRule selects.
+func NewRBACValidatorWithAggregation(ctx context.Context, clusterRole *rbacv1.ClusterRole, lister ClusterRoleLister) (*RBACValidator, error) {
+	rules, err := ResolveAggregatedRules(ctx, clusterRole, lister)
+	if err != nil {
+		return nil, err
+	}
+	return NewRBACValidator(rules), nil
+}
@@ -0,0 +1,91 @@
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// fakeClusterRoleLister is an in-memory ClusterRoleLister used by tests.
+type fakeClusterRoleLister struct {
+	clusterRoles []rbacv1.ClusterRole
+}
+
+func (f *fakeClusterRoleLister) ListClusterRoles(_ context.Context, selector labels.Selector) ([]rbacv1.ClusterRole, error) {
+	var matched []rbacv1.ClusterRole
+	for _, cr := range f.clusterRoles {
+		if selector.Matches(labels.Set(cr.Labels)) {
+			matched = append(matched, cr)
+		}
+	}
+	return matched, nil
+}
+
+func TestResolveAggregatedRules(t *testing.T) {
+	managerRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-operator-manager-role"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list"}},
+		},
+		AggregationRule: &rbacv1.AggregationRule{
+			ClusterRoleSelectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{AggregateToManagerLabel: "true"}},
+			},
+		},
+	}
+
+	lister := &fakeClusterRoleLister{
+		clusterRoles: []rbacv1.ClusterRole{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "byo-issuer-extra-permissions",
+					Labels: map[string]string{AggregateToManagerLabel: "true"},
+				},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{"byo-issuer.example.com"}, Resources: []string{"issuers"}, Verbs: []string{"get", "list", "watch"}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated-role"},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{"example.com"}, Resources: []string{"widgets"}, Verbs: []string{"*"}},
+				},
+			},
+		},
+	}
+
+	effective, err := ResolveAggregatedRules(context.Background(), managerRole, lister)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(effective) != 2 {
+		t.Fatalf("expected own rules plus the matching satellite's rules, got %d: %v", len(effective), effective)
+	}
+
+	requiredRole := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "byo-issuer-controller"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"byo-issuer.example.com"}, Resources: []string{"issuers"}, Verbs: []string{"get", "watch"}},
+		},
+	}
+
+	t.Run("validator built from the manager role alone cannot grant it", func(t *testing.T) {
+		validator := NewRBACValidator(managerRole.Rules)
+		if err := validator.ValidateRoleCreation(requiredRole); err == nil {
+			t.Error("expected validation to fail without the aggregated satellite rules")
+		}
+	})
+
+	t.Run("aggregated validator can grant it", func(t *testing.T) {
+		validator, err := NewRBACValidatorWithAggregation(context.Background(), managerRole, lister)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := validator.ValidateRoleCreation(requiredRole); err != nil {
+			t.Errorf("expected validation to pass with aggregated rules, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// ValidateClusterRoleCreation checks if the operator can create the given
+// ClusterRole without privilege escalation. Unlike ValidateRoleCreation, the
+// target may itself have a non-nil AggregationRule, in which case its
+// effective Rules (its own Rules plus those of every satellite ClusterRole its
+// selectors match) are resolved via lister before being compared against the
+// operator's permissions.
+func (v *RBACValidator) ValidateClusterRoleCreation(ctx context.Context, clusterRole rbacv1.ClusterRole, lister ClusterRoleLister) error {
+	rules := clusterRole.Rules
+	if clusterRole.AggregationRule != nil {
+		resolved, err := ResolveAggregatedRules(ctx, &clusterRole, lister)
+		if err != nil {
+			return fmt.Errorf("failed to resolve aggregation for clusterrole %s: %w", clusterRole.Name, err)
+		}
+		rules = resolved
+	}
+
+	covered, uncovered := Covers(v.operatorRules, rules)
+	if !covered {
+		return fmt.Errorf("operator cannot create clusterrole %s: missing permissions for %s",
+			clusterRole.Name, formatPolicyRules(uncovered))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateClusterRoleCreation(t *testing.T) {
+	t.Run("plain clusterrole without aggregation", func(t *testing.T) {
+		validator := NewRBACValidator([]rbacv1.PolicyRule{
+			{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+		})
+
+		ok := rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-metrics-reader"},
+			Rules: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+			},
+		}
+		if err := validator.ValidateClusterRoleCreation(context.Background(), ok, nil); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+
+		missingURL := rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-health-reader"},
+			Rules: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+			},
+		}
+		if err := validator.ValidateClusterRoleCreation(context.Background(), missingURL, nil); err == nil {
+			t.Error("expected error for ungranted non-resource URL")
+		}
+	})
+
+	t.Run("aggregated clusterrole resolves satellites before comparing", func(t *testing.T) {
+		validator := NewRBACValidator([]rbacv1.PolicyRule{
+			{APIGroups: []string{"byo-issuer.example.com"}, Resources: []string{"issuers"}, Verbs: []string{"get", "list", "watch"}},
+		})
+
+		aggregated := rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-operator-manager-role"},
+			AggregationRule: &rbacv1.AggregationRule{
+				ClusterRoleSelectors: []metav1.LabelSelector{
+					{MatchLabels: map[string]string{AggregateToManagerLabel: "true"}},
+				},
+			},
+		}
+
+		lister := &fakeClusterRoleLister{
+			clusterRoles: []rbacv1.ClusterRole{
+				{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{AggregateToManagerLabel: "true"}},
+					Rules: []rbacv1.PolicyRule{
+						{APIGroups: []string{"byo-issuer.example.com"}, Resources: []string{"issuers"}, Verbs: []string{"get", "list", "watch"}},
+					},
+				},
+			},
+		}
+
+		if err := validator.ValidateClusterRoleCreation(context.Background(), aggregated, lister); err != nil {
+			t.Errorf("expected no error once satellites are resolved, got: %v", err)
+		}
+	})
+}
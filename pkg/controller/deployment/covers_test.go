@@ -0,0 +1,147 @@
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestCovers(t *testing.T) {
+	tests := []struct {
+		name          string
+		ownerRules    []rbacv1.PolicyRule
+		servantRules  []rbacv1.PolicyRule
+		expectCovered bool
+		expectUncover []rbacv1.PolicyRule
+	}{
+		{
+			name: "*/subresource wildcard covers the same subresource on any base resource",
+			ownerRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"*/status"}, Verbs: []string{"update"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods/status"}, Verbs: []string{"update"}},
+			},
+			expectCovered: true,
+		},
+		{
+			name: "resource/* does not cover that resource's subresources",
+			ownerRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets/*"}, Verbs: []string{"get"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets/status"}, Verbs: []string{"get"}},
+			},
+			expectCovered: false,
+			expectUncover: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets/status"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "base resource does not cover its subresource",
+			ownerRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts"}, Verbs: []string{"create"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+			},
+			expectCovered: false,
+			expectUncover: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+			},
+		},
+		{
+			name: "non-resource URL prefix wildcard covers nested path",
+			ownerRules: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/logs/*"}, Verbs: []string{"get"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/logs/foo"}, Verbs: []string{"get"}},
+			},
+			expectCovered: true,
+		},
+		{
+			name: "non-resource URL not granted",
+			ownerRules: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+			},
+			expectCovered: false,
+			expectUncover: []rbacv1.PolicyRule{
+				{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "wildcard verb, resource and group cover anything",
+			ownerRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+			},
+			expectCovered: true,
+		},
+		{
+			name: "owner rule without resourceNames covers any resourceNames",
+			ownerRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"create"}},
+			},
+			expectCovered: true,
+		},
+		{
+			name: "owner rule restricted by resourceNames does not cover unrestricted request",
+			ownerRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"create"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+			},
+			expectCovered: false,
+			expectUncover: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+			},
+		},
+		{
+			name: "verbs split across multiple owner rules for the same resource",
+			ownerRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}},
+			},
+			expectCovered: true,
+		},
+		{
+			name: "empty APIGroup matches only core group",
+			ownerRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+			},
+			servantRules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+			},
+			expectCovered: false,
+			expectUncover: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			covered, uncovered := Covers(tt.ownerRules, tt.servantRules)
+			if covered != tt.expectCovered {
+				t.Errorf("expected covered=%v, got %v (uncovered=%v)", tt.expectCovered, covered, uncovered)
+			}
+			if !tt.expectCovered && !reflect.DeepEqual(uncovered, tt.expectUncover) {
+				t.Errorf("expected uncovered=%v, got %v", tt.expectUncover, uncovered)
+			}
+		})
+	}
+}
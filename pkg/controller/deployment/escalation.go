@@ -0,0 +1,86 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// RoleGetter resolves the Role or ClusterRole a binding refers to, so the
+// escalation check can compare the operator's permissions against what the
+// binding would actually grant. Production wires this to a client; tests
+// inject a fixture-backed implementation.
+type RoleGetter interface {
+	GetRole(ctx context.Context, namespace, name string) (*rbacv1.Role, error)
+	GetClusterRole(ctx context.Context, name string) (*rbacv1.ClusterRole, error)
+}
+
+// checkBindingEscalation implements the "escalation check" the upstream
+// Kubernetes RBAC authorizer applies to every RoleBinding/ClusterRoleBinding
+// write: the operator may bind roleRef only if it either holds the "bind"
+// verb on roleRef (scoped by ResourceNames to roleRef.Name), or it already
+// possesses every permission roleRef itself grants. The latter is resolved
+// via roleGetter; a referenced ClusterRole's AggregationRule, if any, is
+// trusted as already unwrapped into its Rules (as the apiserver's
+// aggregation controller keeps them in sync).
+func (v *RBACValidator) checkBindingEscalation(ctx context.Context, namespace string, roleRef rbacv1.RoleRef, roleGetter RoleGetter) error {
+	if v.canBindRole(roleRef) {
+		return nil
+	}
+
+	rules, err := resolveRoleRefRules(ctx, namespace, roleRef, roleGetter)
+	if err != nil {
+		return fmt.Errorf("missing bind permission on %s %s, and failed to resolve its rules to check for an escalation exemption: %w",
+			roleRef.Kind, roleRef.Name, err)
+	}
+
+	covered, uncovered := Covers(v.operatorRules, rules)
+	if !covered {
+		return fmt.Errorf("missing bind permission on %s %s, and operator does not already hold every rule it grants: missing %s",
+			roleRef.Kind, roleRef.Name, formatPolicyRules(minimizeRules(uncovered)))
+	}
+
+	return nil
+}
+
+// resolveRoleRefRules fetches the Role or ClusterRole named by roleRef and
+// returns its Rules. namespace scopes a Role lookup; it is ignored for a
+// ClusterRole, which is cluster-scoped.
+func resolveRoleRefRules(ctx context.Context, namespace string, roleRef rbacv1.RoleRef, roleGetter RoleGetter) ([]rbacv1.PolicyRule, error) {
+	switch roleRef.Kind {
+	case "ClusterRole":
+		clusterRole, err := roleGetter.GetClusterRole(ctx, roleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		return clusterRole.Rules, nil
+	case "Role":
+		role, err := roleGetter.GetRole(ctx, namespace, roleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		return role.Rules, nil
+	default:
+		return nil, fmt.Errorf("unsupported roleRef kind %q", roleRef.Kind)
+	}
+}
+
+// canBindRole reports whether the operator holds the "bind" verb on the kind
+// of object roleRef refers to (roles or clusterroles), scoped by
+// ResourceNames to roleRef.Name.
+func (v *RBACValidator) canBindRole(roleRef rbacv1.RoleRef) bool {
+	resource := "roles"
+	if roleRef.Kind == "ClusterRole" {
+		resource = "clusterroles"
+	}
+
+	bindRule := rbacv1.PolicyRule{
+		APIGroups:     []string{rbacv1.GroupName},
+		Resources:     []string{resource},
+		Verbs:         []string{"bind"},
+		ResourceNames: []string{roleRef.Name},
+	}
+	covered, _ := Covers(v.operatorRules, []rbacv1.PolicyRule{bindRule})
+	return covered
+}
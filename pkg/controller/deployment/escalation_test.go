@@ -0,0 +1,30 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// fakeRoleGetter is an in-memory RoleGetter used by tests.
+type fakeRoleGetter struct {
+	roles        map[string]*rbacv1.Role
+	clusterRoles map[string]*rbacv1.ClusterRole
+}
+
+func (f *fakeRoleGetter) GetRole(_ context.Context, namespace, name string) (*rbacv1.Role, error) {
+	role, ok := f.roles[namespace+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("role %s/%s not found", namespace, name)
+	}
+	return role, nil
+}
+
+func (f *fakeRoleGetter) GetClusterRole(_ context.Context, name string) (*rbacv1.ClusterRole, error) {
+	clusterRole, ok := f.clusterRoles[name]
+	if !ok {
+		return nil, fmt.Errorf("clusterrole %s not found", name)
+	}
+	return clusterRole, nil
+}
@@ -0,0 +1,29 @@
+package deployment
+
+import (
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetMissingPermissions_Minimized(t *testing.T) {
+	validator := NewRBACValidator(nil)
+
+	role := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tokenrequest"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"create"}},
+		},
+	}
+
+	missing := validator.GetMissingPermissions(role)
+
+	if len(missing) != 1 {
+		t.Fatalf("expected rules sharing APIGroups+Verbs to merge into one, got %d: %v", len(missing), missing)
+	}
+	if len(missing[0].Resources) != 2 {
+		t.Errorf("expected merged rule to union both resources, got %v", missing[0].Resources)
+	}
+}
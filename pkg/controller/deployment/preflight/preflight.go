@@ -0,0 +1,173 @@
+// Package preflight wires deployment.RBACValidator into the operator's
+// reconcile loop as a startup and change-triggered gate: before the operator
+// creates or updates any downstream Role, it confirms its own ClusterRole
+// still covers every permission those Roles need, rather than letting the
+// apiserver reject the write with an opaque "attempt to grant extra
+// privileges" error.
+package preflight
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/openshift/cert-manager-operator/pkg/controller/deployment"
+)
+
+// ReasonInsufficientRBAC is the condition reason and event reason reported
+// when the operator's ClusterRole no longer covers every Role it must
+// create.
+const ReasonInsufficientRBAC = "InsufficientRBAC"
+
+// ConditionDegraded is the condition type the gate sets, mirroring the
+// operator-status Degraded condition convention used across OpenShift
+// operators.
+const ConditionDegraded = "Degraded"
+
+// ConditionStatus mirrors operatorv1.ConditionStatus without requiring this
+// package to depend on the CertManager CR's API package.
+type ConditionStatus string
+
+const (
+	ConditionTrue  ConditionStatus = "True"
+	ConditionFalse ConditionStatus = "False"
+)
+
+// Condition is the subset of operatorv1.OperatorCondition the gate needs to
+// report.
+type Condition struct {
+	Type    string
+	Status  ConditionStatus
+	Reason  string
+	Message string
+}
+
+// ConditionSetter is implemented by whatever tracks the CertManager CR's
+// status conditions (typically backed by library-go's
+// v1helpers.SetOperatorCondition), decoupling this package from that type.
+type ConditionSetter interface {
+	SetCondition(Condition)
+}
+
+// Gate blocks reconciliation on RBAC gaps found by validator, surfacing them
+// as a Degraded condition and a Warning event on eventObject.
+type Gate struct {
+	validator   *deployment.RBACValidator
+	recorder    record.EventRecorder
+	eventObject runtime.Object
+}
+
+// NewGate creates a preflight Gate. recorder and eventObject may be nil, in
+// which case no Kubernetes Event is emitted (useful for the file-based
+// developer workflow, as opposed to the in-cluster one).
+func NewGate(validator *deployment.RBACValidator, recorder record.EventRecorder, eventObject runtime.Object) *Gate {
+	return &Gate{
+		validator:   validator,
+		recorder:    recorder,
+		eventObject: eventObject,
+	}
+}
+
+// Check validates every role in rolesToCreate against the gate's validator.
+// It always updates conditions, and returns false plus a human-readable
+// remediation message if any role is missing permissions.
+func (g *Gate) Check(rolesToCreate []rbacv1.Role, conditions ConditionSetter) (ok bool, remediation string) {
+	errs := g.validator.ValidateAllRoles(rolesToCreate)
+	if len(errs) == 0 {
+		conditions.SetCondition(Condition{
+			Type:   ConditionDegraded,
+			Status: ConditionFalse,
+			Reason: ReasonInsufficientRBAC,
+		})
+		return true, ""
+	}
+
+	remediation = g.formatRemediation(rolesToCreate, errs)
+
+	conditions.SetCondition(Condition{
+		Type:    ConditionDegraded,
+		Status:  ConditionTrue,
+		Reason:  ReasonInsufficientRBAC,
+		Message: remediation,
+	})
+
+	if g.recorder != nil && g.eventObject != nil {
+		g.recorder.Event(g.eventObject, corev1.EventTypeWarning, ReasonInsufficientRBAC, remediation)
+	}
+
+	return false, remediation
+}
+
+// ReasonRBACPolicyViolation is the condition reason and event reason
+// reported when a deployment.RBACPolicy finds wildcard grants or other
+// security anti-patterns on the operator's own ClusterRole.
+const ReasonRBACPolicyViolation = "RBACPolicyViolation"
+
+// CheckPolicy evaluates policy against clusterRoleRules and reports any
+// violations through the same condition/event surface as Check. Whether a
+// violation blocks reconciliation (ok=false) depends on policy.Enforcement:
+// EnforceRBAC blocks, WarnRBAC and AuditRBAC only report.
+func (g *Gate) CheckPolicy(policy *deployment.RBACPolicy, clusterRoleRules []rbacv1.PolicyRule, conditions ConditionSetter) (ok bool, violations []deployment.Violation) {
+	violations = policy.Evaluate(clusterRoleRules)
+	if len(violations) == 0 {
+		conditions.SetCondition(Condition{
+			Type:   ConditionDegraded,
+			Status: ConditionFalse,
+			Reason: ReasonRBACPolicyViolation,
+		})
+		return true, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "RBAC policy (%s) found %d violation(s) on the operator ClusterRole:\n", policy.Enforcement, len(violations))
+	for _, v := range violations {
+		fmt.Fprintf(&b, "- [%s] %s\n", v.Check, v.Message)
+	}
+	message := b.String()
+
+	blocks := policy.Blocks(violations)
+	status := ConditionFalse
+	if blocks {
+		status = ConditionTrue
+	}
+
+	conditions.SetCondition(Condition{
+		Type:    ConditionDegraded,
+		Status:  status,
+		Reason:  ReasonRBACPolicyViolation,
+		Message: message,
+	})
+
+	if g.recorder != nil && g.eventObject != nil {
+		eventType := corev1.EventTypeWarning
+		g.recorder.Event(g.eventObject, eventType, ReasonRBACPolicyViolation, message)
+	}
+
+	return !blocks, violations
+}
+
+// formatRemediation turns validation errors into a message actionable via
+// `oc describe certmanager cluster`: one line per failing role plus the
+// kubebuilder annotation that would fix it.
+func (g *Gate) formatRemediation(rolesToCreate []rbacv1.Role, errs []error) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "operator ClusterRole is missing permissions required by %d role(s) cert-manager needs:\n", len(errs))
+
+	for _, role := range rolesToCreate {
+		missing := g.validator.GetMissingPermissions(role)
+		if len(missing) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "- role %s is missing:\n", role.Name)
+		for _, suggestion := range g.validator.SuggestKubebuilderAnnotation(missing) {
+			fmt.Fprintf(&b, "    %s\n", suggestion)
+		}
+	}
+
+	b.WriteString("add the suggested kubebuilder:rbac annotations, run `make manifests`, and reapply config/rbac/role.yaml")
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package preflight
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/openshift/cert-manager-operator/pkg/controller/deployment"
+)
+
+type fakeConditions struct {
+	conditions []Condition
+}
+
+func (f *fakeConditions) SetCondition(c Condition) {
+	f.conditions = append(f.conditions, c)
+}
+
+func TestGateCheck(t *testing.T) {
+	tokenRequestRole := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tokenrequest"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"create"}},
+		},
+	}
+
+	t.Run("reports not-degraded when permissions are sufficient", func(t *testing.T) {
+		validator := deployment.NewRBACValidator([]rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+		})
+		fakeRecorder := record.NewFakeRecorder(1)
+		gate := NewGate(validator, fakeRecorder, &corev1.ObjectReference{Name: "cert-manager-operator"})
+		conditions := &fakeConditions{}
+
+		ok, remediation := gate.Check([]rbacv1.Role{tokenRequestRole}, conditions)
+
+		if !ok || remediation != "" {
+			t.Fatalf("expected gate to pass, got ok=%v remediation=%q", ok, remediation)
+		}
+		if len(conditions.conditions) != 1 || conditions.conditions[0].Status != ConditionFalse {
+			t.Errorf("expected a single Degraded=False condition, got %v", conditions.conditions)
+		}
+		select {
+		case event := <-fakeRecorder.Events:
+			t.Errorf("expected no event to be emitted, got %q", event)
+		default:
+		}
+	})
+
+	t.Run("degrades and emits a remediation event on RBAC gap", func(t *testing.T) {
+		validator := deployment.NewRBACValidator(nil)
+		fakeRecorder := record.NewFakeRecorder(1)
+		gate := NewGate(validator, fakeRecorder, &corev1.ObjectReference{Name: "cert-manager-operator"})
+		conditions := &fakeConditions{}
+
+		ok, remediation := gate.Check([]rbacv1.Role{tokenRequestRole}, conditions)
+
+		if ok {
+			t.Fatal("expected gate to fail")
+		}
+		if !strings.Contains(remediation, "cert-manager-tokenrequest") {
+			t.Errorf("expected remediation to name the failing role, got %q", remediation)
+		}
+		if len(conditions.conditions) != 1 || conditions.conditions[0].Status != ConditionTrue {
+			t.Errorf("expected a single Degraded=True condition, got %v", conditions.conditions)
+		}
+
+		select {
+		case event := <-fakeRecorder.Events:
+			if !strings.Contains(event, ReasonInsufficientRBAC) {
+				t.Errorf("expected event to reference %s, got %q", ReasonInsufficientRBAC, event)
+			}
+		default:
+			t.Error("expected a Warning event to be emitted")
+		}
+	})
+}
+
+func TestGateCheckPolicy(t *testing.T) {
+	wildcardRules := []rbacv1.PolicyRule{
+		{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+	}
+
+	t.Run("enforce mode blocks on violation", func(t *testing.T) {
+		gate := NewGate(deployment.NewRBACValidator(nil), nil, nil)
+		conditions := &fakeConditions{}
+
+		ok, violations := gate.CheckPolicy(deployment.DefaultRBACPolicy(), wildcardRules, conditions)
+
+		if ok {
+			t.Error("expected EnforceRBAC policy to block")
+		}
+		if len(violations) == 0 {
+			t.Error("expected violations to be reported")
+		}
+		if len(conditions.conditions) != 1 || conditions.conditions[0].Status != ConditionTrue {
+			t.Errorf("expected Degraded=True, got %v", conditions.conditions)
+		}
+	})
+
+	t.Run("relaxed warn-mode profile allows wildcards and does not block", func(t *testing.T) {
+		gate := NewGate(deployment.NewRBACValidator(nil), nil, nil)
+		conditions := &fakeConditions{}
+
+		ok, violations := gate.CheckPolicy(deployment.RelaxedRBACPolicy(), wildcardRules, conditions)
+
+		if !ok {
+			t.Error("expected WarnRBAC policy not to block")
+		}
+		if len(violations) != 0 {
+			t.Errorf("expected relaxed profile to allow wildcards, got %v", violations)
+		}
+	})
+}
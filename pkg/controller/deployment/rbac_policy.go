@@ -0,0 +1,211 @@
+package deployment
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// Enforcement mirrors the PodSecurity admission levels (Enforce/Warn/Audit):
+// it controls whether an RBACPolicy violation blocks validation outright or
+// is merely surfaced for visibility.
+type Enforcement string
+
+const (
+	// EnforceRBAC blocks the preflight gate (Gate.CheckPolicy) on violation.
+	EnforceRBAC Enforcement = "Enforce"
+	// WarnRBAC reports violations but does not block.
+	WarnRBAC Enforcement = "Warn"
+	// AuditRBAC records violations without surfacing them as failures.
+	AuditRBAC Enforcement = "Audit"
+)
+
+// Violation is a single PolicyCheck failure against one PolicyRule.
+type Violation struct {
+	Check   string
+	Rule    rbacv1.PolicyRule
+	Message string
+}
+
+// PolicyCheck is one pluggable rule an RBACPolicy evaluates against every
+// PolicyRule in a Role/ClusterRole.
+type PolicyCheck interface {
+	// Name identifies the check, used as Violation.Check.
+	Name() string
+	// Evaluate returns a violation message for rule, or "" if rule passes.
+	Evaluate(rule rbacv1.PolicyRule) string
+}
+
+// RBACPolicy runs a set of PolicyChecks against policy rules and, depending
+// on Enforcement, either blocks or merely reports the violations found.
+type RBACPolicy struct {
+	Enforcement Enforcement
+	Checks      []PolicyCheck
+}
+
+// NewRBACPolicy builds a policy engine from the given enforcement level and
+// checks.
+func NewRBACPolicy(enforcement Enforcement, checks ...PolicyCheck) *RBACPolicy {
+	return &RBACPolicy{
+		Enforcement: enforcement,
+		Checks:      checks,
+	}
+}
+
+// Evaluate runs every check against every rule and returns all violations
+// found, regardless of Enforcement level.
+func (p *RBACPolicy) Evaluate(rules []rbacv1.PolicyRule) []Violation {
+	var violations []Violation
+	for _, rule := range rules {
+		for _, check := range p.Checks {
+			if msg := check.Evaluate(rule); msg != "" {
+				violations = append(violations, Violation{
+					Check:   check.Name(),
+					Rule:    rule,
+					Message: msg,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// EvaluateRole evaluates a Role's Rules.
+func (p *RBACPolicy) EvaluateRole(role rbacv1.Role) []Violation {
+	return p.Evaluate(role.Rules)
+}
+
+// EvaluateClusterRole evaluates a ClusterRole's Rules.
+func (p *RBACPolicy) EvaluateClusterRole(clusterRole rbacv1.ClusterRole) []Violation {
+	return p.Evaluate(clusterRole.Rules)
+}
+
+// Blocks reports whether violations should fail validation under this
+// policy's Enforcement level.
+func (p *RBACPolicy) Blocks(violations []Violation) bool {
+	return p.Enforcement == EnforceRBAC && len(violations) > 0
+}
+
+// --- built-in checks ---
+
+type noWildcardAPIGroups struct{}
+
+func (noWildcardAPIGroups) Name() string { return "NoWildcardAPIGroups" }
+func (noWildcardAPIGroups) Evaluate(rule rbacv1.PolicyRule) string {
+	for _, group := range rule.APIGroups {
+		if group == "*" {
+			return "wildcard API group '*' is not allowed; list the specific groups needed"
+		}
+	}
+	return ""
+}
+
+// NoWildcardAPIGroups rejects rules granting every API group via "*".
+func NoWildcardAPIGroups() PolicyCheck { return noWildcardAPIGroups{} }
+
+type noWildcardResources struct{}
+
+func (noWildcardResources) Name() string { return "NoWildcardResources" }
+func (noWildcardResources) Evaluate(rule rbacv1.PolicyRule) string {
+	for _, resource := range rule.Resources {
+		if resource == "*" {
+			return "wildcard resource '*' is not allowed; list the specific resources needed"
+		}
+	}
+	return ""
+}
+
+// NoWildcardResources rejects rules granting every resource via "*".
+func NoWildcardResources() PolicyCheck { return noWildcardResources{} }
+
+type noWildcardVerbs struct{}
+
+func (noWildcardVerbs) Name() string { return "NoWildcardVerbs" }
+func (noWildcardVerbs) Evaluate(rule rbacv1.PolicyRule) string {
+	for _, verb := range rule.Verbs {
+		if verb == "*" {
+			return "wildcard verb '*' is not allowed; list the specific verbs needed"
+		}
+	}
+	return ""
+}
+
+// NoWildcardVerbs rejects rules granting every verb via "*".
+func NoWildcardVerbs() PolicyCheck { return noWildcardVerbs{} }
+
+type denyVerbs struct {
+	verbs []string
+}
+
+func (denyVerbs) Name() string { return "DenyVerbs" }
+func (d denyVerbs) Evaluate(rule rbacv1.PolicyRule) string {
+	for _, verb := range rule.Verbs {
+		for _, denied := range d.verbs {
+			if verb == denied {
+				return fmt.Sprintf("verb %q is not allowed by policy", verb)
+			}
+		}
+	}
+	return ""
+}
+
+// DenyVerbs rejects rules granting any of the given verbs, e.g. "escalate"
+// or "impersonate".
+func DenyVerbs(verbs []string) PolicyCheck { return denyVerbs{verbs: verbs} }
+
+type maxResourceCardinality struct {
+	max int
+}
+
+func (maxResourceCardinality) Name() string { return "MaxResourceCardinality" }
+func (m maxResourceCardinality) Evaluate(rule rbacv1.PolicyRule) string {
+	if len(rule.Resources) > m.max {
+		return fmt.Sprintf("rule grants %d resources, which exceeds the policy maximum of %d", len(rule.Resources), m.max)
+	}
+	return ""
+}
+
+// MaxResourceCardinality rejects rules that grant permissions across more
+// than max distinct resources, a proxy for overly broad grants.
+func MaxResourceCardinality(max int) PolicyCheck { return maxResourceCardinality{max: max} }
+
+type requireResourceNamesFor struct {
+	resource string
+}
+
+func (requireResourceNamesFor) Name() string { return "RequireResourceNamesFor" }
+func (r requireResourceNamesFor) Evaluate(rule rbacv1.PolicyRule) string {
+	for _, resource := range rule.Resources {
+		if resource == r.resource && len(rule.ResourceNames) == 0 {
+			return fmt.Sprintf("rule grants %q without ResourceNames; scope it to specific object names", r.resource)
+		}
+	}
+	return ""
+}
+
+// RequireResourceNamesFor rejects rules that grant the given resource
+// without restricting it via ResourceNames.
+func RequireResourceNamesFor(resource string) PolicyCheck {
+	return requireResourceNamesFor{resource: resource}
+}
+
+// DefaultRBACPolicy is the cert-manager-tuned profile applied to the
+// operator's own ClusterRole: no wildcards anywhere, and the classic
+// privilege-escalation verbs denied outright.
+func DefaultRBACPolicy() *RBACPolicy {
+	return NewRBACPolicy(EnforceRBAC,
+		NoWildcardAPIGroups(),
+		NoWildcardResources(),
+		NoWildcardVerbs(),
+		DenyVerbs([]string{"escalate", "impersonate"}),
+	)
+}
+
+// RelaxedRBACPolicy only denies escalate/impersonate, in Warn mode. It suits
+// the historically broad cert-manager controller Role, which legitimately
+// needs create/delete/update across many core resources.
+func RelaxedRBACPolicy() *RBACPolicy {
+	return NewRBACPolicy(WarnRBAC,
+		DenyVerbs([]string{"escalate", "impersonate"}),
+	)
+}
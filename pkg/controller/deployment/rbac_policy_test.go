@@ -0,0 +1,78 @@
+package deployment
+
+import (
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestRBACPolicy_DefaultProfile(t *testing.T) {
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+	}
+
+	policy := DefaultRBACPolicy()
+	violations := policy.Evaluate(rules)
+
+	if len(violations) != 3 {
+		t.Fatalf("expected 3 violations (group, resource, verb wildcards), got %d: %v", len(violations), violations)
+	}
+	if !policy.Blocks(violations) {
+		t.Error("expected EnforceRBAC policy to block on violations")
+	}
+}
+
+func TestRBACPolicy_RelaxedProfile(t *testing.T) {
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps", "events", "namespaces", "pods", "secrets", "serviceaccounts", "services"}, Verbs: []string{"create", "delete", "get", "list", "patch", "update", "watch"}},
+	}
+
+	policy := RelaxedRBACPolicy()
+	violations := policy.Evaluate(rules)
+
+	if len(violations) != 0 {
+		t.Errorf("expected the historically broad controller Role to pass the relaxed profile, got %v", violations)
+	}
+
+	escalating := []rbacv1.PolicyRule{
+		{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, Verbs: []string{"escalate"}},
+	}
+	violations = policy.Evaluate(escalating)
+	if len(violations) != 1 {
+		t.Fatalf("expected escalate verb to be denied even under the relaxed profile, got %v", violations)
+	}
+	if policy.Blocks(violations) {
+		t.Error("expected WarnRBAC policy not to block, only report")
+	}
+}
+
+func TestRequireResourceNamesFor(t *testing.T) {
+	policy := NewRBACPolicy(EnforceRBAC, RequireResourceNamesFor("serviceaccounts/token"))
+
+	unscoped := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+	}
+	if violations := policy.Evaluate(unscoped); len(violations) != 1 {
+		t.Errorf("expected unscoped serviceaccounts/token grant to violate policy, got %v", violations)
+	}
+
+	scoped := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"create"}},
+	}
+	if violations := policy.Evaluate(scoped); len(violations) != 0 {
+		t.Errorf("expected scoped serviceaccounts/token grant to pass policy, got %v", violations)
+	}
+}
+
+func TestMaxResourceCardinality(t *testing.T) {
+	policy := NewRBACPolicy(EnforceRBAC, MaxResourceCardinality(2))
+
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps", "secrets", "events"}, Verbs: []string{"get"}},
+	}
+
+	violations := policy.Evaluate(rules)
+	if len(violations) != 1 {
+		t.Fatalf("expected a single cardinality violation, got %v", violations)
+	}
+}
@@ -2,15 +2,27 @@ package deployment
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // RBACValidator validates that operator permissions are sufficient for resources it creates
 type RBACValidator struct {
 	operatorRules []rbacv1.PolicyRule
 	createdRoles  []rbacv1.Role
+
+	// The fields below are only set by NewRBACValidatorFromClient, and back
+	// ValidateRoleCreationLive's cluster-backed checks (see
+	// rbac_validator_live.go).
+	client         kubernetes.Interface
+	namespace      string
+	liveCacheMu    sync.Mutex
+	liveCache      map[liveCacheKey]bool
+	effectiveRules []rbacv1.PolicyRule
 }
 
 // NewRBACValidator creates a new RBAC validator
@@ -20,56 +32,269 @@ func NewRBACValidator(operatorRules []rbacv1.PolicyRule) *RBACValidator {
 	}
 }
 
-// ValidateRoleCreation checks if operator can create the given role without privilege escalation
+// ValidateRoleCreation checks if operator can create the given role without privilege escalation.
+// It reports the exact residual permissions the operator is missing, mirroring the
+// "rule covers rule" semantics used by the upstream Kubernetes RBAC authorizer.
 func (v *RBACValidator) ValidateRoleCreation(role rbacv1.Role) error {
-	for _, rule := range role.Rules {
-		if !v.operatorCanGrant(rule) {
-			return fmt.Errorf("operator cannot create role %s: missing permissions for %v",
-				role.Name, formatPolicyRule(rule))
-		}
+	covered, uncovered := Covers(v.operatorRules, role.Rules)
+	if !covered {
+		return fmt.Errorf("operator cannot create role %s: missing permissions for %s",
+			role.Name, formatPolicyRules(minimizeRules(uncovered)))
 	}
 	return nil
 }
 
+// minimizeRules merges rules that share identical APIGroups and Verbs into a
+// single rule with the union of their Resources, so callers like
+// SuggestKubebuilderAnnotation see one compact grant instead of one rule per
+// requested role.
+func minimizeRules(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	type key struct {
+		groups string
+		verbs  string
+	}
+
+	order := make([]key, 0, len(rules))
+	merged := make(map[key]*rbacv1.PolicyRule, len(rules))
+
+	for _, rule := range rules {
+		k := key{
+			groups: sortedJoin(rule.APIGroups),
+			verbs:  sortedJoin(rule.Verbs),
+		}
+		existing, ok := merged[k]
+		if !ok {
+			ruleCopy := rule
+			merged[k] = &ruleCopy
+			order = append(order, k)
+			continue
+		}
+		existing.Resources = mergeUnique(existing.Resources, rule.Resources)
+		existing.ResourceNames = mergeUnique(existing.ResourceNames, rule.ResourceNames)
+		existing.NonResourceURLs = mergeUnique(existing.NonResourceURLs, rule.NonResourceURLs)
+	}
+
+	out := make([]rbacv1.PolicyRule, 0, len(order))
+	for _, k := range order {
+		out = append(out, *merged[k])
+	}
+	return out
+}
+
+// sortedJoin returns a stable, order-independent key for a string slice.
+func sortedJoin(values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// mergeUnique returns the union of a and b, preserving the order of a
+// followed by any new entries from b.
+func mergeUnique(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	seen := make(map[string]bool, len(a))
+	out := append([]string{}, a...)
+	for _, s := range a {
+		seen[s] = true
+	}
+	for _, s := range b {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
 // operatorCanGrant checks if operator has sufficient permissions to grant a policy rule
 func (v *RBACValidator) operatorCanGrant(rule rbacv1.PolicyRule) bool {
-	for _, opRule := range v.operatorRules {
-		if v.ruleCovers(opRule, rule) {
-			return true
+	covered, _ := Covers(v.operatorRules, []rbacv1.PolicyRule{rule})
+	return covered
+}
+
+// Covers determines whether ownerRules grants every permission expressed by servantRules.
+// It returns false plus the residual servantRules (or, for a rule only partially granted,
+// the ungranted fragment of it) that the owner does not already cover. Each verb of a
+// servant rule is checked on its own, and its resources (or non-resource URLs) may be
+// granted by different owner rules. A servant rule's APIGroups and ResourceNames are not
+// split: for a given verb and resource, a single owner rule must grant all of them, so
+// a grant spread over several owner rules by group or by resource name is reported as
+// uncovered.
+func Covers(ownerRules, servantRules []rbacv1.PolicyRule) (bool, []rbacv1.PolicyRule) {
+	var uncoveredRules []rbacv1.PolicyRule
+
+	for _, servantRule := range servantRules {
+		uncoveredRules = append(uncoveredRules, uncoveredForRule(ownerRules, servantRule)...)
+	}
+
+	return len(uncoveredRules) == 0, uncoveredRules
+}
+
+// uncoveredForRule checks servantRule against ownerRules one verb at a time and returns
+// whatever remains uncovered. Real RBAC permissions routinely come from separate owner
+// rules for the same resource (one rule granting "get", another "list"); checking the
+// whole verb set against a single owner rule at once would report a false gap whenever
+// no single owner rule happens to grant every verb servantRule asks for. Per-verb
+// fragments that leave the same resources or non-resource URLs uncovered are merged
+// back into one rule before being returned.
+func uncoveredForRule(ownerRules []rbacv1.PolicyRule, servantRule rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	var uncovered []rbacv1.PolicyRule
+
+	for _, verb := range servantRule.Verbs {
+		remaining := servantRule
+		remaining.Verbs = []string{verb}
+
+		for _, ownerRule := range ownerRules {
+			var covered bool
+			covered, remaining = subtractRule(ownerRule, remaining)
+			if covered {
+				break
+			}
+		}
+		if ruleIsEmpty(remaining) {
+			continue
+		}
+
+		merged := false
+		for i := range uncovered {
+			if sortedJoin(uncovered[i].Resources) == sortedJoin(remaining.Resources) &&
+				sortedJoin(uncovered[i].NonResourceURLs) == sortedJoin(remaining.NonResourceURLs) {
+				uncovered[i].Verbs = append(uncovered[i].Verbs, verb)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			uncovered = append(uncovered, remaining)
 		}
 	}
-	return false
+
+	return minimizeRules(uncovered)
 }
 
-// ruleCovers checks if the operator rule covers the required rule
-func (v *RBACValidator) ruleCovers(opRule, reqRule rbacv1.PolicyRule) bool {
-	// Check API groups
-	if !v.sliceContains(opRule.APIGroups, reqRule.APIGroups) {
-		return false
+// subtractRule reports whether ownerRule fully covers rule, and if not, returns the
+// portion of rule (resources/nonResourceURLs still needing cover) that remains uncovered.
+// rule is expected to carry a single verb; Covers expands multi-verb rules before calling
+// this so that verbs granted by different owner rules are each credited independently.
+func subtractRule(ownerRule, rule rbacv1.PolicyRule) (bool, rbacv1.PolicyRule) {
+	if !verbMatches(ownerRule, rule) {
+		return false, rule
 	}
 
-	// Check resources
-	if !v.sliceContains(opRule.Resources, reqRule.Resources) {
-		return false
+	if len(rule.NonResourceURLs) > 0 {
+		var uncoveredURLs []string
+		for _, url := range rule.NonResourceURLs {
+			if !nonResourceURLMatches(ownerRule, url) {
+				uncoveredURLs = append(uncoveredURLs, url)
+			}
+		}
+		if len(uncoveredURLs) == 0 {
+			return true, rbacv1.PolicyRule{}
+		}
+		remaining := rule
+		remaining.NonResourceURLs = uncoveredURLs
+		return false, remaining
 	}
 
-	// Check verbs
-	if !v.sliceContains(opRule.Verbs, reqRule.Verbs) {
+	if !apiGroupMatches(ownerRule, rule) {
+		return false, rule
+	}
+
+	var uncoveredResources []string
+	for _, resource := range rule.Resources {
+		if !resourceMatches(ownerRule, resource) {
+			uncoveredResources = append(uncoveredResources, resource)
+			continue
+		}
+		if !resourceNameMatches(ownerRule, rule.ResourceNames) {
+			uncoveredResources = append(uncoveredResources, resource)
+		}
+	}
+	if len(uncoveredResources) == 0 {
+		return true, rbacv1.PolicyRule{}
+	}
+
+	remaining := rule
+	remaining.Resources = uncoveredResources
+	return false, remaining
+}
+
+// verbMatches returns true if ownerRule grants every verb in rule.
+func verbMatches(ownerRule, rule rbacv1.PolicyRule) bool {
+	return stringSliceCoversAll(ownerRule.Verbs, rule.Verbs)
+}
+
+// apiGroupMatches returns true if ownerRule grants every API group in rule.
+func apiGroupMatches(ownerRule, rule rbacv1.PolicyRule) bool {
+	return stringSliceCoversAll(ownerRule.APIGroups, rule.APIGroups)
+}
+
+// resourceMatches returns true if ownerRule grants the given resource, including
+// subresource semantics: a rule for "*/status" covers any resource's "status"
+// subresource (e.g. "deployments/status", "pods/status"), matching upstream
+// RBAC's ResourceMatches. A rule for "secrets/*" does not cover "secrets/status";
+// the apiserver has no such wildcard and neither does this validator.
+func resourceMatches(ownerRule rbacv1.PolicyRule, resource string) bool {
+	for _, ownedResource := range ownerRule.Resources {
+		if ownedResource == "*" {
+			return true
+		}
+		if ownedResource == resource {
+			return true
+		}
+
+		if ownerBase, ownerSub, ok := splitSubresource(ownedResource); ok && ownerBase == "*" {
+			if _, requestSub, hasSub := splitSubresource(resource); hasSub && requestSub == ownerSub {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resourceNameMatches returns true if ownerRule's ResourceNames restriction (if any)
+// covers every name in requiredNames. An ownerRule with no ResourceNames is unrestricted
+// and covers any ResourceNames. An ownerRule with specific ResourceNames only covers a
+// requiredNames set that is non-empty and a subset of its own.
+func resourceNameMatches(ownerRule rbacv1.PolicyRule, requiredNames []string) bool {
+	if len(ownerRule.ResourceNames) == 0 {
+		return true
+	}
+	if len(requiredNames) == 0 {
 		return false
 	}
+	return stringSliceCoversAll(ownerRule.ResourceNames, requiredNames)
+}
 
-	// Check resource names if specified
-	if len(reqRule.ResourceNames) > 0 && len(opRule.ResourceNames) > 0 {
-		if !v.sliceContains(opRule.ResourceNames, reqRule.ResourceNames) {
-			return false
+// nonResourceURLMatches returns true if ownerRule grants the given non-resource URL,
+// honoring the "/*" path-prefix wildcard (e.g. "/logs/*" covers "/logs/foo").
+func nonResourceURLMatches(ownerRule rbacv1.PolicyRule, url string) bool {
+	for _, ownedURL := range ownerRule.NonResourceURLs {
+		if ownedURL == "*" || ownedURL == url {
+			return true
+		}
+		if strings.HasSuffix(ownedURL, "/*") && strings.HasPrefix(url, strings.TrimSuffix(ownedURL, "*")) {
+			return true
 		}
 	}
+	return false
+}
 
-	return true
+// splitSubresource splits "resource/subresource" into its parts. ok is false if resource
+// has no subresource component.
+func splitSubresource(resource string) (base, sub string, ok bool) {
+	idx := strings.Index(resource, "/")
+	if idx < 0 {
+		return resource, "", false
+	}
+	return resource[:idx], resource[idx+1:], true
 }
 
-// sliceContains checks if all items in required are present in available
-func (v *RBACValidator) sliceContains(available, required []string) bool {
+// stringSliceCoversAll reports whether available covers every entry in required, treating
+// "*" in available as matching any value.
+func stringSliceCoversAll(available, required []string) bool {
 	for _, req := range required {
 		found := false
 		for _, avail := range available {
@@ -85,12 +310,30 @@ func (v *RBACValidator) sliceContains(available, required []string) bool {
 	return true
 }
 
+// ruleIsEmpty reports whether rule has no resources or non-resource URLs left to grant,
+// meaning it has been fully covered.
+func ruleIsEmpty(rule rbacv1.PolicyRule) bool {
+	return len(rule.Resources) == 0 && len(rule.NonResourceURLs) == 0
+}
+
 // formatPolicyRule formats a policy rule for error messages
 func formatPolicyRule(rule rbacv1.PolicyRule) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return fmt.Sprintf("NonResourceURLs:%v Verbs:%v", rule.NonResourceURLs, rule.Verbs)
+	}
 	return fmt.Sprintf("APIGroups:%v Resources:%v Verbs:%v",
 		rule.APIGroups, rule.Resources, rule.Verbs)
 }
 
+// formatPolicyRules formats a list of policy rules for error messages
+func formatPolicyRules(rules []rbacv1.PolicyRule) string {
+	formatted := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		formatted = append(formatted, formatPolicyRule(rule))
+	}
+	return strings.Join(formatted, "; ")
+}
+
 // ValidateAllRoles validates all roles that the operator will create
 func (v *RBACValidator) ValidateAllRoles(roles []rbacv1.Role) []error {
 	var errors []error
@@ -104,15 +347,8 @@ func (v *RBACValidator) ValidateAllRoles(roles []rbacv1.Role) []error {
 
 // GetMissingPermissions returns the permissions the operator needs to create a role
 func (v *RBACValidator) GetMissingPermissions(role rbacv1.Role) []rbacv1.PolicyRule {
-	var missing []rbacv1.PolicyRule
-
-	for _, rule := range role.Rules {
-		if !v.operatorCanGrant(rule) {
-			missing = append(missing, rule)
-		}
-	}
-
-	return missing
+	_, uncovered := Covers(v.operatorRules, role.Rules)
+	return minimizeRules(uncovered)
 }
 
 // SuggestKubebuilderAnnotation suggests the kubebuilder annotation needed for missing permissions
@@ -120,6 +356,13 @@ func (v *RBACValidator) SuggestKubebuilderAnnotation(missingRules []rbacv1.Polic
 	var suggestions []string
 
 	for _, rule := range missingRules {
+		if len(rule.NonResourceURLs) > 0 {
+			urls := strings.Join(rule.NonResourceURLs, ";")
+			verbs := strings.Join(rule.Verbs, ";")
+			suggestions = append(suggestions, fmt.Sprintf("//+kubebuilder:rbac:urls=%s,verbs=%s", urls, verbs))
+			continue
+		}
+
 		apiGroups := strings.Join(rule.APIGroups, ";")
 		if apiGroups == "" {
 			apiGroups = `""`
@@ -0,0 +1,215 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// liveCacheKey identifies one SelfSubjectAccessReview result, cached for the
+// lifetime of the RBACValidator so repeated checks for the same permission
+// don't re-hit the API server.
+type liveCacheKey struct {
+	namespace string
+	group     string
+	resource  string
+	verb      string
+	name      string
+}
+
+// NewRBACValidatorFromClient creates an RBACValidator that checks permissions
+// against the live cluster, rather than a static []rbacv1.PolicyRule. It
+// issues SelfSubjectAccessReview requests scoped to namespace (use "" for
+// cluster-scoped checks), closing the gap between static manifest analysis
+// and what the cluster actually enforces (webhooks, admission plugins, and
+// aggregated authorizers can all differ from the ClusterRole).
+func NewRBACValidatorFromClient(client kubernetes.Interface, namespace string) *RBACValidator {
+	return &RBACValidator{
+		client:    client,
+		namespace: namespace,
+		liveCache: make(map[liveCacheKey]bool),
+	}
+}
+
+// ValidateRoleCreationLive checks, against the live cluster, whether the
+// operator's ServiceAccount actually holds every permission role.Rules
+// requires. It first consults SelfSubjectRulesReview to precompute the
+// caller's effective rules for a cheap batch comparison, then falls back to
+// per-permission SelfSubjectAccessReview calls for anything that static
+// comparison can't settle, appending the SSAR's Status.Reason to the error.
+func (v *RBACValidator) ValidateRoleCreationLive(ctx context.Context, role rbacv1.Role) error {
+	if v.client == nil {
+		return fmt.Errorf("RBACValidator has no client; use NewRBACValidatorFromClient")
+	}
+
+	effectiveRules, err := v.effectiveRulesReview(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to compute effective rules via SelfSubjectRulesReview: %w", err)
+	}
+
+	covered, uncovered := Covers(effectiveRules, role.Rules)
+	if covered {
+		return nil
+	}
+
+	var denials []string
+	for _, rule := range minimizeRules(uncovered) {
+		for _, group := range orEmptyGroup(rule.APIGroups) {
+			for _, resource := range rule.Resources {
+				for _, verb := range rule.Verbs {
+					names := rule.ResourceNames
+					if len(names) == 0 {
+						names = []string{""}
+					}
+					for _, name := range names {
+						allowed, reason, err := v.checkAccess(ctx, group, resource, verb, name)
+						if err != nil {
+							return fmt.Errorf("SelfSubjectAccessReview failed for role %s: %w", role.Name, err)
+						}
+						if !allowed {
+							denials = append(denials, formatDenial(group, resource, verb, name, reason))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if len(denials) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("operator cannot create role %s: missing permissions for %s",
+		role.Name, strings.Join(denials, "; "))
+}
+
+// checkAccess issues (and caches) a SelfSubjectAccessReview for one
+// (group, resource, verb, resourceName) tuple. name is part of the cache key:
+// access to one resourceName must never be reused as a cached answer for a
+// different one.
+func (v *RBACValidator) checkAccess(ctx context.Context, group, resource, verb, name string) (allowed bool, reason string, err error) {
+	key := liveCacheKey{namespace: v.namespace, group: group, resource: resource, verb: verb, name: name}
+
+	v.liveCacheMu.Lock()
+	if cached, ok := v.liveCache[key]; ok {
+		v.liveCacheMu.Unlock()
+		return cached, "", nil
+	}
+	v.liveCacheMu.Unlock()
+
+	ssar := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   v.namespace,
+				Verb:        verb,
+				Group:       group,
+				Resource:    resourceBase(resource),
+				Name:        name,
+				Subresource: resourceSubresource(resource),
+			},
+		},
+	}
+
+	result, err := v.client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", err
+	}
+
+	v.liveCacheMu.Lock()
+	v.liveCache[key] = result.Status.Allowed
+	v.liveCacheMu.Unlock()
+
+	return result.Status.Allowed, result.Status.Reason, nil
+}
+
+// effectiveRulesReview fetches (and caches) the caller's effective rules in
+// v.namespace via SelfSubjectRulesReview, converting them to []PolicyRule so
+// they can be run through the same Covers logic as the static path.
+func (v *RBACValidator) effectiveRulesReview(ctx context.Context) ([]rbacv1.PolicyRule, error) {
+	v.liveCacheMu.Lock()
+	if v.effectiveRules != nil {
+		rules := v.effectiveRules
+		v.liveCacheMu.Unlock()
+		return rules, nil
+	}
+	v.liveCacheMu.Unlock()
+
+	review := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{
+			Namespace: v.namespace,
+		},
+	}
+
+	result, err := v.client.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []rbacv1.PolicyRule
+	for _, r := range result.Status.ResourceRules {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     r.APIGroups,
+			Resources:     r.Resources,
+			Verbs:         r.Verbs,
+			ResourceNames: r.ResourceNames,
+		})
+	}
+	for _, r := range result.Status.NonResourceRules {
+		rules = append(rules, rbacv1.PolicyRule{
+			NonResourceURLs: r.NonResourceURLs,
+			Verbs:           r.Verbs,
+		})
+	}
+
+	if rules == nil {
+		rules = []rbacv1.PolicyRule{}
+	}
+
+	v.liveCacheMu.Lock()
+	v.effectiveRules = rules
+	v.liveCacheMu.Unlock()
+
+	return rules, nil
+}
+
+// orEmptyGroup returns groups, or a single "" group if groups is empty, so
+// the SSAR loop always runs at least once for core-group rules.
+func orEmptyGroup(groups []string) []string {
+	if len(groups) == 0 {
+		return []string{""}
+	}
+	return groups
+}
+
+// resourceBase and resourceSubresource split "resource/subresource" the way
+// SelfSubjectAccessReview's ResourceAttributes expects them: as separate
+// Resource and Subresource fields rather than a single slash-joined string.
+func resourceBase(resource string) string {
+	base, _, _ := splitSubresource(resource)
+	return base
+}
+
+func resourceSubresource(resource string) string {
+	_, sub, _ := splitSubresource(resource)
+	return sub
+}
+
+func formatDenial(group, resource, verb, name, reason string) string {
+	target := resource
+	if group != "" {
+		target = group + "/" + resource
+	}
+	if name != "" {
+		target += fmt.Sprintf(" (name=%s)", name)
+	}
+	denial := fmt.Sprintf("%s:%s", verb, target)
+	if reason != "" {
+		denial += fmt.Sprintf(" (%s)", reason)
+	}
+	return denial
+}
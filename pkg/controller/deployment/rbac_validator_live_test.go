@@ -0,0 +1,97 @@
+package deployment
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestValidateRoleCreationLive(t *testing.T) {
+	role := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tokenrequest"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"create"}},
+		},
+	}
+
+	t.Run("effective rules from SelfSubjectRulesReview already cover the role", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := &authorizationv1.SelfSubjectRulesReview{
+				Status: authorizationv1.SubjectRulesReviewStatus{
+					ResourceRules: []authorizationv1.ResourceRule{
+						{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+					},
+				},
+			}
+			return true, review, nil
+		})
+
+		validator := NewRBACValidatorFromClient(client, "cert-manager")
+		if err := validator.ValidateRoleCreationLive(context.Background(), role); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("falls back to SelfSubjectAccessReview and reports the denial reason", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, &authorizationv1.SelfSubjectRulesReview{}, nil
+		})
+		client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			ssar := &authorizationv1.SelfSubjectAccessReview{
+				Status: authorizationv1.SubjectAccessReviewStatus{
+					Allowed: false,
+					Reason:  "RBAC: permission denied",
+				},
+			}
+			return true, ssar, nil
+		})
+
+		validator := NewRBACValidatorFromClient(client, "cert-manager")
+		err := validator.ValidateRoleCreationLive(context.Background(), role)
+		if err == nil {
+			t.Fatal("expected error when the live SSAR denies the request")
+		}
+		if !strings.Contains(err.Error(), "RBAC: permission denied") {
+			t.Errorf("expected error to surface the SSAR denial reason, got: %v", err)
+		}
+	})
+}
+
+func TestCheckAccess_CachePerResourceName(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		create := action.(k8stesting.CreateAction)
+		ssar := create.GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		allowed := ssar.Spec.ResourceAttributes.Name == "allowed-secret"
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: allowed},
+		}, nil
+	})
+
+	validator := NewRBACValidatorFromClient(client, "cert-manager")
+
+	allowed, _, err := validator.checkAccess(context.Background(), "", "secrets", "get", "allowed-secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !allowed {
+		t.Fatal("expected access to allowed-secret to be allowed")
+	}
+
+	forbidden, _, err := validator.checkAccess(context.Background(), "", "secrets", "get", "forbidden-secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if forbidden {
+		t.Error("expected access to forbidden-secret to be denied, got cached allowed result from a different resourceName")
+	}
+}
@@ -0,0 +1,256 @@
+// Package rbacannotate turns the residual PolicyRules reported by
+// deployment.RBACValidator into source changes: it walks a controller
+// package, finds each Reconcile method (or an explicit rbac-target marker),
+// and inserts the missing //+kubebuilder:rbac annotations above it, so a
+// developer no longer has to hand-translate SuggestKubebuilderAnnotation
+// output back into the right file.
+package rbacannotate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/openshift/cert-manager-operator/pkg/controller/deployment"
+)
+
+// Marker is the comment a developer can place immediately above any
+// function to designate it as the RBAC annotation target in a file that has
+// no Reconcile method.
+const Marker = "// +cert-manager-operator:rbac-target"
+
+// ApplyOptions configures ApplyKubebuilderAnnotations.
+type ApplyOptions struct {
+	// DryRun computes the Report and the diffs it would produce, without
+	// writing any file to disk.
+	DryRun bool
+	// Check fails with an error (instead of writing) if any target is
+	// missing required annotations, for use as a CI gate.
+	Check bool
+}
+
+// FileChange describes the annotations ApplyKubebuilderAnnotations inserted
+// (or would insert) into a single file.
+type FileChange struct {
+	Path        string
+	Func        string
+	Inserted    []string
+	UnifiedDiff string
+}
+
+// Report summarizes one ApplyKubebuilderAnnotations run.
+type Report struct {
+	Changes []FileChange
+}
+
+// ApplyKubebuilderAnnotations walks the Go files under pkgDir, locates each
+// controller's Reconcile method (or a function marked with Marker), and
+// inserts the //+kubebuilder:rbac annotations for missing, deduplicating
+// against annotations already present above that function. Each rule in
+// missing is rendered as its own annotation by
+// deployment.RBACValidator.SuggestKubebuilderAnnotation; rules that share
+// identical verbs are not grouped or merged.
+func ApplyKubebuilderAnnotations(pkgDir string, missing []rbacv1.PolicyRule, opts ApplyOptions) (Report, error) {
+	validator := deployment.NewRBACValidator(nil)
+	suggestions := validator.SuggestKubebuilderAnnotation(missing)
+	if len(suggestions) == 0 {
+		return Report{}, nil
+	}
+
+	var report Report
+
+	err := filepath.WalkDir(pkgDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		changed, err := applyToFile(path, suggestions, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		report.Changes = append(report.Changes, changed...)
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if opts.Check && len(report.Changes) > 0 {
+		var missingFuncs []string
+		for _, c := range report.Changes {
+			missingFuncs = append(missingFuncs, fmt.Sprintf("%s:%s", c.Path, c.Func))
+		}
+		sort.Strings(missingFuncs)
+		return report, fmt.Errorf("missing kubebuilder:rbac annotations on %s; run `make manifests` after applying them", strings.Join(missingFuncs, ", "))
+	}
+
+	return report, nil
+}
+
+// applyToFile finds every rbac-target function in one file and inserts
+// suggestions above it, returning one FileChange per function touched.
+func applyToFile(path string, suggestions []string, opts ApplyOptions) ([]FileChange, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+
+	var changes []FileChange
+	var mutated bool
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !isRBACTarget(fn) {
+			continue
+		}
+
+		existing := commentLines(fn.Doc)
+		toInsert := dedupeAgainst(existing, suggestions)
+		if len(toInsert) == 0 {
+			continue
+		}
+
+		appendAnnotations(fn, toInsert)
+		mutated = true
+
+		changes = append(changes, FileChange{
+			Path:     path,
+			Func:     fn.Name.Name,
+			Inserted: toInsert,
+		})
+	}
+
+	if !mutated {
+		return nil, nil
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read original: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, fmt.Errorf("failed to render formatted source: %w", err)
+	}
+
+	diff := unifiedDiff(path, string(original), buf.String())
+	for i := range changes {
+		changes[i].UnifiedDiff = diff
+	}
+
+	if opts.DryRun || opts.Check {
+		return changes, nil
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write: %w", err)
+	}
+
+	return changes, nil
+}
+
+// isRBACTarget reports whether fn is the annotation insertion point: either
+// a method named Reconcile, or any function whose doc comment contains
+// Marker.
+func isRBACTarget(fn *ast.FuncDecl) bool {
+	if fn.Recv != nil && fn.Name.Name == "Reconcile" {
+		return true
+	}
+	for _, line := range commentLines(fn.Doc) {
+		if strings.TrimSpace(line) == Marker {
+			return true
+		}
+	}
+	return false
+}
+
+// commentLines returns the raw "//..." text of each line in group, or nil.
+func commentLines(group *ast.CommentGroup) []string {
+	if group == nil {
+		return nil
+	}
+	lines := make([]string, 0, len(group.List))
+	for _, c := range group.List {
+		lines = append(lines, c.Text)
+	}
+	return lines
+}
+
+// dedupeAgainst returns the subset of suggestions not already present
+// verbatim in existing.
+func dedupeAgainst(existing, suggestions []string) []string {
+	present := make(map[string]bool, len(existing))
+	for _, line := range existing {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	var out []string
+	for _, s := range suggestions {
+		if !present[strings.TrimSpace(s)] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// appendAnnotations appends lines to fn's doc comment group, creating one if
+// fn had none, and keeps it positioned immediately above fn so it is
+// recognized as part of the function's manifest-generating comment block. A
+// freshly created CommentGroup must be placed strictly before fn.Pos() (the
+// "func" keyword) rather than at it, or go/format splices the comment in
+// between "func" and the receiver instead of rendering it as a leading doc
+// comment.
+func appendAnnotations(fn *ast.FuncDecl, lines []string) {
+	pos := fn.Pos()
+	if fn.Doc != nil {
+		pos = fn.Doc.Pos()
+	} else if fn.Pos() > 0 {
+		pos = fn.Pos() - 1
+	}
+
+	newList := make([]*ast.Comment, 0, len(lines))
+	for _, line := range lines {
+		newList = append(newList, &ast.Comment{Slash: pos, Text: line})
+	}
+
+	if fn.Doc == nil {
+		fn.Doc = &ast.CommentGroup{List: newList}
+		return
+	}
+	fn.Doc.List = append(fn.Doc.List, newList...)
+}
+
+// unifiedDiff renders a minimal line-oriented diff (not full unified-diff
+// context hunks) sufficient for a developer or CI log to review the change.
+func unifiedDiff(path, before, after string) string {
+	beforeLines := strings.Split(before, "\n")
+	afterLines := strings.Split(after, "\n")
+
+	beforeSet := make(map[string]bool, len(beforeLines))
+	for _, l := range beforeLines {
+		beforeSet[l] = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+	for _, l := range afterLines {
+		if !beforeSet[l] {
+			fmt.Fprintf(&b, "+%s\n", l)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,144 @@
+package rbacannotate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+const fixtureController = `package fixture
+
+type TokenRequestController struct{}
+
+// Reconcile issues a ServiceAccount token for cert-manager.
+func (c *TokenRequestController) Reconcile() error {
+	return nil
+}
+`
+
+const fixtureControllerNoDoc = `package fixture
+
+type TokenRequestController struct{}
+
+func (c *TokenRequestController) Reconcile() error {
+	return nil
+}
+`
+
+func writeFixture(t *testing.T) string {
+	t.Helper()
+	return writeFixtureSource(t, fixtureController)
+}
+
+func writeFixtureSource(t *testing.T, source string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "controller.go")
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	return dir
+}
+
+func TestApplyKubebuilderAnnotations(t *testing.T) {
+	missing := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"create"}},
+	}
+
+	dir := writeFixture(t)
+	controllerPath := filepath.Join(dir, "controller.go")
+
+	t.Run("dry run leaves the file untouched but reports the diff", func(t *testing.T) {
+		report, err := ApplyKubebuilderAnnotations(dir, missing, ApplyOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Changes) != 1 {
+			t.Fatalf("expected 1 file change, got %d", len(report.Changes))
+		}
+		if !strings.Contains(report.Changes[0].UnifiedDiff, "+//+kubebuilder:rbac") {
+			t.Errorf("expected diff to show the inserted annotation, got %q", report.Changes[0].UnifiedDiff)
+		}
+
+		data, err := os.ReadFile(controllerPath)
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		if strings.Contains(string(data), "kubebuilder:rbac") {
+			t.Error("dry run must not modify the file on disk")
+		}
+	})
+
+	t.Run("check mode fails without writing", func(t *testing.T) {
+		_, err := ApplyKubebuilderAnnotations(dir, missing, ApplyOptions{Check: true})
+		if err == nil {
+			t.Fatal("expected check mode to fail when annotations are missing")
+		}
+
+		data, err := os.ReadFile(controllerPath)
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		if strings.Contains(string(data), "kubebuilder:rbac") {
+			t.Error("check mode must not modify the file on disk")
+		}
+	})
+
+	t.Run("apply writes the annotation above Reconcile", func(t *testing.T) {
+		report, err := ApplyKubebuilderAnnotations(dir, missing, ApplyOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Changes) != 1 {
+			t.Fatalf("expected 1 file change, got %d", len(report.Changes))
+		}
+
+		data, err := os.ReadFile(controllerPath)
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		if !strings.Contains(string(data), `//+kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create,resourceNames=cert-manager`) {
+			t.Errorf("expected annotation to be inserted above Reconcile, got:\n%s", data)
+		}
+	})
+
+	t.Run("re-running against the same missing rules converges to a no-op", func(t *testing.T) {
+		report, err := ApplyKubebuilderAnnotations(dir, missing, ApplyOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Changes) != 0 {
+			t.Errorf("expected no further changes once annotations are already present, got %v", report.Changes)
+		}
+
+		if _, err := ApplyKubebuilderAnnotations(dir, missing, ApplyOptions{Check: true}); err != nil {
+			t.Errorf("expected check mode to pass once annotations converge, got: %v", err)
+		}
+	})
+
+	t.Run("Reconcile with no pre-existing doc comment gets a real leading comment, not a spliced func keyword", func(t *testing.T) {
+		dir := writeFixtureSource(t, fixtureControllerNoDoc)
+		controllerPath := filepath.Join(dir, "controller.go")
+
+		if _, err := ApplyKubebuilderAnnotations(dir, missing, ApplyOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(controllerPath)
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		if strings.Contains(string(data), "func //+kubebuilder:rbac") {
+			t.Fatalf("annotation was spliced between \"func\" and the receiver, got:\n%s", data)
+		}
+
+		annotationLine := strings.Index(string(data), "//+kubebuilder:rbac")
+		funcLine := strings.Index(string(data), "func (c *TokenRequestController) Reconcile() error {")
+		if annotationLine < 0 || funcLine < 0 || annotationLine >= funcLine {
+			t.Errorf("expected annotation to appear as a leading comment before Reconcile, got:\n%s", data)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+// Package rbacreconciler turns a set of residual (missing) RBAC permissions,
+// as computed by deployment.RBACValidator, into an actionable fix: either a
+// rewritten config/rbac/role.yaml for the developer workflow, or a live
+// server-side-apply patch against the operator's ClusterRole.
+package rbacreconciler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/cert-manager-operator/pkg/controller/deployment"
+)
+
+// ManagerClusterRoleName is the name of the operator's own ClusterRole, as
+// deployed by config/rbac/role.yaml.
+const ManagerClusterRoleName = "cert-manager-operator-manager-role"
+
+// fieldManager is the SSA field manager used when patching the live ClusterRole.
+const fieldManager = "cert-manager-operator-rbac-reconciler"
+
+// ComputeResidualRules computes the minimal set of PolicyRules the operator's
+// ClusterRole is missing in order to grant every Role in rolesToGrant, and
+// merges rules that share identical (APIGroups, Resources, ResourceNames) into
+// a single rule with the union of their verbs.
+func ComputeResidualRules(operatorRules []rbacv1.PolicyRule, rolesToGrant []rbacv1.Role) []rbacv1.PolicyRule {
+	var residual []rbacv1.PolicyRule
+	for _, role := range rolesToGrant {
+		_, uncovered := deployment.Covers(operatorRules, role.Rules)
+		residual = append(residual, uncovered...)
+	}
+	return mergeRules(residual)
+}
+
+// mergeRules merges rules that share identical APIGroups, Resources and
+// ResourceNames into a single rule with the union of their Verbs.
+func mergeRules(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	type key struct {
+		groups    string
+		resources string
+		names     string
+	}
+
+	order := make([]key, 0, len(rules))
+	merged := make(map[key]*rbacv1.PolicyRule, len(rules))
+
+	for _, rule := range rules {
+		k := key{
+			groups:    fmt.Sprint(rule.APIGroups),
+			resources: fmt.Sprint(rule.Resources),
+			names:     fmt.Sprint(rule.ResourceNames),
+		}
+		existing, ok := merged[k]
+		if !ok {
+			ruleCopy := rule
+			merged[k] = &ruleCopy
+			order = append(order, k)
+			continue
+		}
+		existing.Verbs = unionStrings(existing.Verbs, rule.Verbs)
+	}
+
+	out := make([]rbacv1.PolicyRule, 0, len(order))
+	for _, k := range order {
+		out = append(out, *merged[k])
+	}
+	return out
+}
+
+// unionStrings returns the union of a and b, preserving the order of a
+// followed by any new entries from b.
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	out := make([]string, 0, len(a)+len(b))
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Diff describes the change a reconcile would make to a ClusterRole: the
+// rules it holds today and the rules it would hold after the residual
+// permissions are merged in.
+type Diff struct {
+	Before []rbacv1.PolicyRule
+	After  []rbacv1.PolicyRule
+}
+
+// diffFor builds the before/after rule sets for a reconcile of clusterRole
+// against residual, without mutating clusterRole.
+func diffFor(clusterRole *rbacv1.ClusterRole, residual []rbacv1.PolicyRule) Diff {
+	return Diff{
+		Before: clusterRole.Rules,
+		After:  mergeRules(append(append([]rbacv1.PolicyRule{}, clusterRole.Rules...), residual...)),
+	}
+}
+
+// Unchanged reports whether reconciling residual into Before actually changed
+// anything. Comparing len(After) to len(Before) is not reliable: mergeRules
+// unions a residual rule's verbs into an existing Before entry that shares
+// its (APIGroups, Resources, ResourceNames) key, so the rule count can stay
+// identical even though a verb the role needed (e.g. "create") was just
+// added to an entry that already granted "get, list".
+func (d Diff) Unchanged() bool {
+	return reflect.DeepEqual(mergeRules(append([]rbacv1.PolicyRule{}, d.Before...)), d.After)
+}
+
+// WriteClusterRoleFile reads the ClusterRole manifest at path, merges residual
+// into its Rules (preserving existing labels and annotations), and writes the
+// result back to path unless dryRun is set. It returns the computed Diff in
+// either case, so callers can render it for review.
+func WriteClusterRoleFile(path string, residual []rbacv1.PolicyRule, dryRun bool) (Diff, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Diff{}, fmt.Errorf("failed to read ClusterRole manifest %s: %w", path, err)
+	}
+
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(data, clusterRole); err != nil {
+		return Diff{}, fmt.Errorf("failed to parse ClusterRole manifest %s: %w", path, err)
+	}
+
+	diff := diffFor(clusterRole, residual)
+	if diff.Unchanged() {
+		return diff, nil
+	}
+
+	if dryRun {
+		return diff, nil
+	}
+
+	clusterRole.Rules = diff.After
+	out, err := yaml.Marshal(clusterRole)
+	if err != nil {
+		return diff, fmt.Errorf("failed to marshal reconciled ClusterRole: %w", err)
+	}
+
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return diff, fmt.Errorf("failed to write ClusterRole manifest %s: %w", path, err)
+	}
+
+	return diff, nil
+}
+
+// PatchLiveClusterRole submits a server-side-apply patch that adds residual to
+// the live ClusterRole named name, preserving every field the apiserver
+// already owns on behalf of other managers. It is a no-op if merging residual
+// leaves the live ClusterRole's rules unchanged; a residual rule that the live
+// rules grant only through a wildcard or a broader rule is still added.
+func PatchLiveClusterRole(ctx context.Context, client kubernetes.Interface, name string, residual []rbacv1.PolicyRule) (Diff, error) {
+	live, err := client.RbacV1().ClusterRoles().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return Diff{}, fmt.Errorf("failed to get live ClusterRole %s: %w", name, err)
+	}
+
+	diff := diffFor(live, residual)
+	if diff.Unchanged() {
+		return diff, nil
+	}
+
+	apply := &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      live.Labels,
+			Annotations: live.Annotations,
+		},
+		Rules: diff.After,
+	}
+
+	patch, err := yaml.Marshal(apply)
+	if err != nil {
+		return diff, fmt.Errorf("failed to marshal ClusterRole apply patch: %w", err)
+	}
+
+	jsonPatch, err := yaml.YAMLToJSON(patch)
+	if err != nil {
+		return diff, fmt.Errorf("failed to convert ClusterRole apply patch to JSON: %w", err)
+	}
+
+	_, err = client.RbacV1().ClusterRoles().Patch(ctx, name, types.ApplyPatchType, jsonPatch, metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        ptrBool(true),
+	})
+	if err != nil {
+		return diff, fmt.Errorf("failed to apply ClusterRole patch for %s: %w", name, err)
+	}
+
+	return diff, nil
+}
+
+func ptrBool(b bool) *bool {
+	return &b
+}
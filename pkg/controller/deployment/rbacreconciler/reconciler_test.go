@@ -0,0 +1,211 @@
+package rbacreconciler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+)
+
+func TestComputeResidualRules(t *testing.T) {
+	operatorRules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"serviceaccounts"}, Verbs: []string{"get", "list"}},
+	}
+
+	rolesToGrant := []rbacv1.Role{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tokenrequest"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"create"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-leaderelection"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"get"}},
+			},
+		},
+	}
+
+	residual := ComputeResidualRules(operatorRules, rolesToGrant)
+
+	if len(residual) != 1 {
+		t.Fatalf("expected residual rules to merge into a single rule, got %d: %v", len(residual), residual)
+	}
+
+	got := residual[0]
+	if len(got.Verbs) != 2 {
+		t.Errorf("expected merged rule to union verbs from both roles, got %v", got.Verbs)
+	}
+	for _, want := range []string{"create", "get"} {
+		found := false
+		for _, verb := range got.Verbs {
+			if verb == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected merged verbs to include %q, got %v", want, got.Verbs)
+		}
+	}
+}
+
+func TestWriteClusterRoleFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "role.yaml")
+
+	initial := `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: cert-manager-operator-manager-role
+  labels:
+    app: cert-manager-operator
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["get", "list"]
+`
+	if err := os.WriteFile(path, []byte(initial), 0644); err != nil {
+		t.Fatalf("failed to seed fixture: %v", err)
+	}
+
+	residual := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+	}
+
+	diff, err := WriteClusterRoleFile(path, residual, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diff.After) != 2 {
+		t.Errorf("expected dry-run diff to show 2 rules after merge, got %d", len(diff.After))
+	}
+
+	unchanged, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture after dry run: %v", err)
+	}
+	if string(unchanged) != initial {
+		t.Errorf("dry run must not modify the file on disk")
+	}
+
+	if _, err := WriteClusterRoleFile(path, residual, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture after write: %v", err)
+	}
+	role := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(written, role); err != nil {
+		t.Fatalf("failed to parse written ClusterRole: %v", err)
+	}
+	if role.Labels["app"] != "cert-manager-operator" {
+		t.Errorf("expected existing labels to be preserved, got %v", role.Labels)
+	}
+	if len(role.Rules) != 2 {
+		t.Errorf("expected written ClusterRole to have 2 rules, got %d", len(role.Rules))
+	}
+}
+
+func TestWriteClusterRoleFile_ResidualMergesIntoExistingEntry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "role.yaml")
+
+	initial := `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: cert-manager-operator-manager-role
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["get", "list"]
+`
+	if err := os.WriteFile(path, []byte(initial), 0644); err != nil {
+		t.Fatalf("failed to seed fixture: %v", err)
+	}
+
+	residual := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"create"}},
+	}
+
+	diff, err := WriteClusterRoleFile(path, residual, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diff.After) != 1 {
+		t.Fatalf("expected residual to merge into the existing secrets rule, got %d rules: %v", len(diff.After), diff.After)
+	}
+	if diff.Unchanged() {
+		t.Fatal("expected diff.Unchanged() to be false: residual added a verb the role needs")
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture after write: %v", err)
+	}
+	role := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(written, role); err != nil {
+		t.Fatalf("failed to parse written ClusterRole: %v", err)
+	}
+	if len(role.Rules) != 1 {
+		t.Fatalf("expected 1 rule, got %d", len(role.Rules))
+	}
+
+	found := false
+	for _, verb := range role.Rules[0].Verbs {
+		if verb == "create" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the merged rule to grant create even though the rule count didn't change, got verbs %v", role.Rules[0].Verbs)
+	}
+}
+
+func TestPatchLiveClusterRole(t *testing.T) {
+	live := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ManagerClusterRoleName,
+			Labels:      map[string]string{"app": "cert-manager-operator"},
+			Annotations: map[string]string{"include.release.openshift.io/self-managed-high-availability": "true"},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list"}},
+		},
+	}
+
+	client := fake.NewSimpleClientset(live)
+
+	var applied rbacv1.ClusterRole
+	client.PrependReactor("patch", "clusterroles", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if err := yaml.Unmarshal(patch.GetPatch(), &applied); err != nil {
+			t.Fatalf("failed to parse apply patch: %v", err)
+		}
+		return true, &applied, nil
+	})
+
+	residual := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+	}
+
+	if _, err := PatchLiveClusterRole(context.Background(), client, ManagerClusterRoleName, residual); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if applied.Labels["app"] != "cert-manager-operator" {
+		t.Errorf("expected apply patch to preserve existing labels, got %v", applied.Labels)
+	}
+	if applied.Annotations["include.release.openshift.io/self-managed-high-availability"] != "true" {
+		t.Errorf("expected apply patch to preserve existing annotations, got %v", applied.Annotations)
+	}
+}
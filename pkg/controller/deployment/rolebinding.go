@@ -0,0 +1,90 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceAccountRef identifies the ServiceAccount a generated RoleBinding
+// should bind a Role to.
+type ServiceAccountRef struct {
+	Name      string
+	Namespace string
+}
+
+// GenerateRoleBindings materializes a RoleBinding for each Role in roles,
+// binding it to the ServiceAccount registered for that role's name in
+// serviceAccounts. It mirrors the controller-tools GenerateRoleBindings
+// convention of naming the binding "{roleName}binding". Roles with no
+// registered ServiceAccount are skipped.
+func GenerateRoleBindings(roles []rbacv1.Role, serviceAccounts map[string]ServiceAccountRef) []rbacv1.RoleBinding {
+	var bindings []rbacv1.RoleBinding
+
+	for _, role := range roles {
+		sa, ok := serviceAccounts[role.Name]
+		if !ok {
+			continue
+		}
+
+		bindings = append(bindings, rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      role.Name + "binding",
+				Namespace: role.Namespace,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      sa.Name,
+					Namespace: sa.Namespace,
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     role.Name,
+			},
+		})
+	}
+
+	return bindings
+}
+
+// ValidateRoleBindingCreation checks that the operator can create rb without
+// privilege escalation and that the bound ServiceAccount already exists,
+// identified as "namespace/name" in knownServiceAccounts. Escalation is
+// checked via checkBindingEscalation: see its doc for the full semantics.
+func (v *RBACValidator) ValidateRoleBindingCreation(ctx context.Context, rb rbacv1.RoleBinding, knownServiceAccounts map[string]bool, roleGetter RoleGetter) error {
+	if err := v.checkBindingEscalation(ctx, rb.Namespace, rb.RoleRef, roleGetter); err != nil {
+		return fmt.Errorf("operator cannot create rolebinding %s: %w", rb.Name, err)
+	}
+
+	for _, subject := range rb.Subjects {
+		if subject.Kind != rbacv1.ServiceAccountKind {
+			continue
+		}
+		namespace := subject.Namespace
+		if namespace == "" {
+			namespace = rb.Namespace
+		}
+		key := namespace + "/" + subject.Name
+		if !knownServiceAccounts[key] {
+			return fmt.Errorf("operator cannot create rolebinding %s: referenced ServiceAccount %s does not exist",
+				rb.Name, key)
+		}
+	}
+
+	return nil
+}
+
+// ValidateClusterRoleBindingCreation checks that the operator can create crb
+// without privilege escalation, per the same rules as
+// ValidateRoleBindingCreation.
+func (v *RBACValidator) ValidateClusterRoleBindingCreation(ctx context.Context, crb rbacv1.ClusterRoleBinding, roleGetter RoleGetter) error {
+	if err := v.checkBindingEscalation(ctx, "", crb.RoleRef, roleGetter); err != nil {
+		return fmt.Errorf("operator cannot create clusterrolebinding %s: %w", crb.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenerateRoleBindings(t *testing.T) {
+	roles := []rbacv1.Role{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tokenrequest", Namespace: "cert-manager"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-leaderelection", Namespace: "cert-manager"}},
+	}
+
+	serviceAccounts := map[string]ServiceAccountRef{
+		"cert-manager-tokenrequest": {Name: "cert-manager", Namespace: "cert-manager"},
+	}
+
+	bindings := GenerateRoleBindings(roles, serviceAccounts)
+
+	if len(bindings) != 1 {
+		t.Fatalf("expected 1 rolebinding (role without a registered ServiceAccount is skipped), got %d", len(bindings))
+	}
+
+	binding := bindings[0]
+	if binding.Name != "cert-manager-tokenrequestbinding" {
+		t.Errorf("expected binding name %q, got %q", "cert-manager-tokenrequestbinding", binding.Name)
+	}
+	if binding.RoleRef.Name != "cert-manager-tokenrequest" {
+		t.Errorf("expected roleRef name %q, got %q", "cert-manager-tokenrequest", binding.RoleRef.Name)
+	}
+	if len(binding.Subjects) != 1 || binding.Subjects[0].Name != "cert-manager" {
+		t.Errorf("expected subject bound to ServiceAccount cert-manager, got %v", binding.Subjects)
+	}
+}
+
+func TestValidateRoleBindingCreation(t *testing.T) {
+	knownServiceAccounts := map[string]bool{"cert-manager/cert-manager": true}
+
+	// Mirrors the actual cert-manager RoleBinding set: leaderelection,
+	// tokenrequest, and webhook-ca.
+	tokenRequestRole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tokenrequest", Namespace: "cert-manager"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, ResourceNames: []string{"cert-manager"}, Verbs: []string{"create"}},
+		},
+	}
+	leaderElectionRole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-leaderelection", Namespace: "cert-manager"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: []string{"get", "create", "update"}},
+		},
+	}
+	webhookCARole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-webhook-ca", Namespace: "cert-manager"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"cert-manager-webhook-ca"}, Verbs: []string{"get", "update"}},
+		},
+	}
+
+	roleGetter := &fakeRoleGetter{
+		roles: map[string]*rbacv1.Role{
+			"cert-manager/cert-manager-tokenrequest":   tokenRequestRole,
+			"cert-manager/cert-manager-leaderelection": leaderElectionRole,
+			"cert-manager/cert-manager-webhook-ca":     webhookCARole,
+		},
+	}
+
+	rb := rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tokenrequestbinding", Namespace: "cert-manager"},
+		Subjects: []rbacv1.Subject{
+			{Kind: rbacv1.ServiceAccountKind, Name: "cert-manager", Namespace: "cert-manager"},
+		},
+		RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "cert-manager-tokenrequest"},
+	}
+
+	t.Run("fails without bind verb or the underlying serviceaccounts/token create", func(t *testing.T) {
+		validator := NewRBACValidator(nil)
+		if err := validator.ValidateRoleBindingCreation(context.Background(), rb, knownServiceAccounts, roleGetter); err == nil {
+			t.Error("expected error when operator lacks both bind and the underlying rule")
+		}
+	})
+
+	t.Run("passes with scoped bind permission", func(t *testing.T) {
+		validator := NewRBACValidator([]rbacv1.PolicyRule{
+			{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"roles"}, Verbs: []string{"bind"}, ResourceNames: []string{"cert-manager-tokenrequest"}},
+		})
+		if err := validator.ValidateRoleBindingCreation(context.Background(), rb, knownServiceAccounts, roleGetter); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("passes without bind when operator already holds every rule the role grants", func(t *testing.T) {
+		validator := NewRBACValidator([]rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+		})
+		if err := validator.ValidateRoleBindingCreation(context.Background(), rb, knownServiceAccounts, roleGetter); err != nil {
+			t.Errorf("expected no error when the escalation exemption applies, got: %v", err)
+		}
+	})
+
+	t.Run("fails when ServiceAccount does not exist", func(t *testing.T) {
+		validator := NewRBACValidator([]rbacv1.PolicyRule{
+			{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"roles"}, Verbs: []string{"bind"}, ResourceNames: []string{"cert-manager-tokenrequest"}},
+		})
+		if err := validator.ValidateRoleBindingCreation(context.Background(), rb, map[string]bool{}, roleGetter); err == nil {
+			t.Error("expected error when bound ServiceAccount is unknown")
+		}
+	})
+
+	t.Run("bind scoped to a different role does not leak", func(t *testing.T) {
+		validator := NewRBACValidator([]rbacv1.PolicyRule{
+			{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"roles"}, Verbs: []string{"bind"}, ResourceNames: []string{"cert-manager-leaderelection"}},
+		})
+		if err := validator.ValidateRoleBindingCreation(context.Background(), rb, knownServiceAccounts, roleGetter); err == nil {
+			t.Error("expected error: bind on a different role name must not authorize this binding")
+		}
+	})
+
+	t.Run("webhook-ca binding passes when operator holds the underlying rule", func(t *testing.T) {
+		validator := NewRBACValidator([]rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "update"}},
+		})
+		webhookCABinding := rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-webhook-cabinding", Namespace: "cert-manager"},
+			Subjects: []rbacv1.Subject{
+				{Kind: rbacv1.ServiceAccountKind, Name: "cert-manager-webhook", Namespace: "cert-manager"},
+			},
+			RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "cert-manager-webhook-ca"},
+		}
+		known := map[string]bool{"cert-manager/cert-manager-webhook": true}
+		if err := validator.ValidateRoleBindingCreation(context.Background(), webhookCABinding, known, roleGetter); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	})
+}
+
+func TestValidateClusterRoleBindingCreation(t *testing.T) {
+	viewerRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-view"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"cert-manager.io"}, Resources: []string{"certificates"}, Verbs: []string{"get", "list", "watch"}},
+		},
+	}
+	roleGetter := &fakeRoleGetter{
+		clusterRoles: map[string]*rbacv1.ClusterRole{"cert-manager-view": viewerRole},
+	}
+
+	crb := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-viewbinding"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cert-manager-view"},
+	}
+
+	t.Run("fails without bind or the underlying rules", func(t *testing.T) {
+		validator := NewRBACValidator(nil)
+		if err := validator.ValidateClusterRoleBindingCreation(context.Background(), crb, roleGetter); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("passes with scoped bind permission on clusterroles", func(t *testing.T) {
+		validator := NewRBACValidator([]rbacv1.PolicyRule{
+			{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"clusterroles"}, Verbs: []string{"bind"}, ResourceNames: []string{"cert-manager-view"}},
+		})
+		if err := validator.ValidateClusterRoleBindingCreation(context.Background(), crb, roleGetter); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	})
+}